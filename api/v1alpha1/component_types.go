@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ComponentRepositoryLabel marks which Repository a Component was created
+// from.
+const ComponentRepositoryLabel = "core.kubebb.k8s.com.cn/repository"
+
+// DisplayNameAnnotationKey is the chart annotation copied onto
+// ComponentStatus.DisplayName when present.
+const DisplayNameAnnotationKey = "core.kubebb.k8s.com.cn/displayName"
+
+// Maintainer is a chart maintainer, copied from chart.Metadata.Maintainers.
+type Maintainer struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+// ComponentVersion is one resolvable version of a Component's chart.
+type ComponentVersion struct {
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Version     string            `json:"version"`
+	AppVersion  string            `json:"appVersion,omitempty"`
+	CreatedAt   metav1.Time       `json:"createdAt,omitempty"`
+	UpdatedAt   metav1.Time       `json:"updatedAt,omitempty"`
+	Digest      string            `json:"digest,omitempty"`
+	Deprecated  bool              `json:"deprecated,omitempty"`
+
+	// Verified, VerifiedBy and VerifiedAt record the outcome of signature
+	// verification for this version, when the owning Repository sets
+	// spec.verify.
+	// +optional
+	Verified bool `json:"verified,omitempty"`
+	// +optional
+	VerifiedBy VerifyProvider `json:"verifiedBy,omitempty"`
+	// +optional
+	VerifiedAt *metav1.Time `json:"verifiedAt,omitempty"`
+
+	// Variants exposes the per-platform manifests of a multi-arch OCI chart
+	// artifact. A single-manifest chart gets one entry with no platform,
+	// carrying its manifest digest and layer media types.
+	// +optional
+	Variants []ComponentVariant `json:"variants,omitempty"`
+}
+
+// ComponentStatus is the observed state of a Component: the chart metadata
+// and versions discovered from its Repository.
+type ComponentStatus struct {
+	RepositoryRef *v1.ObjectReference `json:"repositoryRef,omitempty"`
+	Name          string              `json:"name,omitempty"`
+	DisplayName   string              `json:"displayName,omitempty"`
+	Description   string              `json:"description,omitempty"`
+	Home          string              `json:"home,omitempty"`
+	Icon          string              `json:"icon,omitempty"`
+	Keywords      []string            `json:"keywords,omitempty"`
+	Sources       []string            `json:"sources,omitempty"`
+	Maintainers   []Maintainer        `json:"maintainers,omitempty"`
+	Versions      []ComponentVersion  `json:"versions,omitempty"`
+
+	// ResolvedTag and ResolvedDigest record the tag and digest actually
+	// selected by spec.ref (tag/digest/semver), so users don't have to
+	// reverse the semver range or digest lookup themselves.
+	// +optional
+	ResolvedTag string `json:"resolvedTag,omitempty"`
+	// +optional
+	ResolvedDigest string `json:"resolvedDigest,omitempty"`
+}
+
+// Component is the Schema for the components API.
+type Component struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status ComponentStatus `json:"status,omitempty"`
+}
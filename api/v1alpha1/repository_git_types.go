@@ -0,0 +1,41 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// GitRepositorySpec configures the `git` ChartSource backend: it clones
+// RepositorySpec.URL at Ref and scans the checkout for Chart.yaml files.
+type GitRepositorySpec struct {
+	// Ref selects what to check out: exactly one of Branch, Tag or Commit.
+	// +optional
+	Ref GitRef `json:"ref,omitempty"`
+
+	// Ignore is a list of .gitignore-style patterns; matching paths are
+	// skipped while scanning for charts.
+	// +optional
+	Ignore []string `json:"ignore,omitempty"`
+}
+
+// GitRef pins a Git checkout the same way RepositoryRef pins an OCI one:
+// exactly one of Branch, Tag or Commit should be set.
+type GitRef struct {
+	// +optional
+	Branch string `json:"branch,omitempty"`
+	// +optional
+	Tag string `json:"tag,omitempty"`
+	// +optional
+	Commit string `json:"commit,omitempty"`
+}
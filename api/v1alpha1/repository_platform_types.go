@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Platform is an os/arch pair, matching the `platform` object of an OCI
+// image index manifest.
+type Platform struct {
+	OS   string `json:"os"`
+	Arch string `json:"architecture"`
+}
+
+// String renders the platform the way OCI tooling conventionally prints it,
+// e.g. "linux/amd64".
+func (p Platform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
+// PlatformSelector, added to RepositorySpec as the `platformSelector` field,
+// restricts ComponentVersion.Variants to the listed platforms when an OCI
+// artifact is an image index (application/vnd.oci.image.index.v1+json)
+// exposing more than one. An empty selector keeps every variant.
+type PlatformSelector []Platform
+
+// Matches reports whether p is empty (keep everything) or contains platform.
+func (p PlatformSelector) Matches(platform Platform) bool {
+	if len(p) == 0 {
+		return true
+	}
+	for _, want := range p {
+		if want == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// ComponentVariant describes one entry of a multi-arch OCI chart artifact:
+// the platform it targets, the manifest digest for that platform, and the
+// media types of its layers. Added as ComponentVersion.Variants.
+type ComponentVariant struct {
+	Platform   Platform `json:"platform"`
+	Digest     string   `json:"digest"`
+	MediaTypes []string `json:"mediaTypes,omitempty"`
+}
+
+// ComponentVersion.Variants (see component_types.go) exposes the variants
+// above for OCI index manifests; a plain single-manifest chart has exactly
+// one implicit variant, recorded with an empty Platform.
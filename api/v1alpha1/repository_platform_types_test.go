@@ -0,0 +1,50 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "testing"
+
+func TestPlatformSelectorMatches(t *testing.T) {
+	linuxAmd64 := Platform{OS: "linux", Arch: "amd64"}
+	linuxArm64 := Platform{OS: "linux", Arch: "arm64"}
+
+	tests := []struct {
+		name     string
+		selector PlatformSelector
+		platform Platform
+		want     bool
+	}{
+		{name: "empty selector keeps everything", selector: nil, platform: linuxAmd64, want: true},
+		{name: "listed platform matches", selector: PlatformSelector{linuxAmd64}, platform: linuxAmd64, want: true},
+		{name: "unlisted platform is rejected", selector: PlatformSelector{linuxAmd64}, platform: linuxArm64, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.selector.Matches(tt.platform); got != tt.want {
+				t.Errorf("Matches(%v) = %v, want %v", tt.platform, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlatformString(t *testing.T) {
+	p := Platform{OS: "linux", Arch: "amd64"}
+	if got, want := p.String(), "linux/amd64"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// RepositoryRef pins an OCI repository to exactly one of a tag, a digest or a
+// semver range. It is added to RepositorySpec as the `ref` field so OCI
+// Repositories can track a precise artifact instead of every tag in the
+// registry.
+//
+// +kubebuilder:validation:XValidation:rule="(has(self.tag)?1:0)+(has(self.digest)?1:0)+(has(self.semver)?1:0)==1",message="exactly one of tag, digest or semver must be set"
+type RepositoryRef struct {
+	// Tag is the name of the image tag to pull.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// Digest is the digest of the image manifest to pull, e.g.
+	// sha256:3c5e0... . Takes precedence over Tag and Semver.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// SemVer is a semver range (e.g. "1.x", ">=1.2.0 <2.0.0") resolved
+	// against the tags available in the registry; the highest matching
+	// tag is selected.
+	// +optional
+	SemVer string `json:"semver,omitempty"`
+}
+
+// RepositoryAuth selects how OCIWatcher authenticates against the registry
+// referenced by RepositorySpec.URL. Exactly one of SecretRef or
+// ServiceAccountName should be set.
+type RepositoryAuth struct {
+	// SecretRef names a Secret of type kubernetes.io/dockerconfigjson in the
+	// Repository's namespace holding registry credentials.
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// ServiceAccountName names a ServiceAccount in the Repository's
+	// namespace; its imagePullSecrets are harvested and used as registry
+	// credentials.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
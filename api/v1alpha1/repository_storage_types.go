@@ -0,0 +1,36 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// StorageType selects where OCIWatcher materializes chart data it pulls
+// from the registry.
+type StorageType string
+
+const (
+	// StorageMemory decodes chart layers straight into memory and never
+	// touches the filesystem. Required for read-only root filesystems and
+	// safe for concurrent polling of many Repositories.
+	StorageMemory StorageType = "Memory"
+
+	// StorageFilesystem is the original behavior: charts are cached on disk
+	// under Helm's repository cache, keyed by repo name.
+	StorageFilesystem StorageType = "Filesystem"
+)
+
+// DefaultStorageType is used by RepositorySpec.Storage when unset, preserving
+// the behavior existing Repositories had before Storage was introduced.
+const DefaultStorageType = StorageFilesystem
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RepositorySpec is the desired state of a Repository.
+type RepositorySpec struct {
+	// URL is the repository location: an index.yaml endpoint for Helm HTTP
+	// repositories, an OCI reference for the helm-oci backend, or the Git
+	// remote the git backend clones.
+	URL string `json:"url"`
+
+	// KeywordLenLimit caps how many chart keywords are copied onto a
+	// Component; 0 means unlimited.
+	// +optional
+	KeywordLenLimit int `json:"keywordLenLimit,omitempty"`
+
+	// Ref pins the OCI reference OCIWatcher resolves to exactly one of a
+	// tag, a digest or a semver range, instead of tracking every tag.
+	// +optional
+	Ref *RepositoryRef `json:"ref,omitempty"`
+
+	// Auth selects the credentials OCIWatcher authenticates to the
+	// registry with.
+	// +optional
+	Auth *RepositoryAuth `json:"auth,omitempty"`
+
+	// Storage selects where OCIWatcher materializes chart data pulled from
+	// the registry. Defaults to DefaultStorageType.
+	// +optional
+	Storage StorageType `json:"storage,omitempty"`
+
+	// Type selects which pkg/repository ChartSource backend serves this
+	// Repository: one of RepositoryTypeHelmHTTP, RepositoryTypeHelmOCI or
+	// RepositoryTypeGit. Defaults to RepositoryTypeHelmHTTP; NewChartSource
+	// applies that default when Type is unset, so pre-existing Repositories
+	// persisted before this field existed keep working unchanged.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Git configures the RepositoryTypeGit backend. Required when Type is
+	// RepositoryTypeGit, ignored otherwise.
+	// +optional
+	Git *GitRepositorySpec `json:"git,omitempty"`
+
+	// Verify requires OCIWatcher to check chart signatures before a
+	// Component is created for them.
+	// +optional
+	Verify *RepositoryVerify `json:"verify,omitempty"`
+
+	// Webhook lets a push event from the registry trigger an immediate Poll
+	// instead of waiting for the next periodic sync.
+	// +optional
+	Webhook *RepositoryWebhook `json:"webhook,omitempty"`
+
+	// PlatformSelector restricts ComponentVersion.Variants to the listed
+	// platforms when an OCI artifact is a multi-arch image index. An empty
+	// selector keeps every variant.
+	// +optional
+	PlatformSelector PlatformSelector `json:"platformSelector,omitempty"`
+}
+
+// RepositoryStatus is the observed state of a Repository.
+type RepositoryStatus struct {
+	// LastWebhookAt records the last time a webhook delivery triggered a
+	// Poll, as opposed to the periodic fallback.
+	// +optional
+	LastWebhookAt *metav1.Time `json:"lastWebhookAt,omitempty"`
+}
+
+// Repository is the Schema for the repositories API.
+type Repository struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RepositorySpec   `json:"spec,omitempty"`
+	Status RepositoryStatus `json:"status,omitempty"`
+}
+
+// NamespacedName returns the "namespace/name" key OCIWatcher uses to key its
+// entry in Helm's repository cache.
+func (r *Repository) NamespacedName() string {
+	return r.GetNamespace() + "/" + r.GetName()
+}
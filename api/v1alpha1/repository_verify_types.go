@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// VerifyProvider selects the signature scheme RepositoryVerify checks
+// pulled charts against.
+type VerifyProvider string
+
+const (
+	VerifyProviderCosign   VerifyProvider = "cosign"
+	VerifyProviderNotation VerifyProvider = "notation"
+)
+
+// MatchPolicy controls what happens to a chart version whose signature
+// cannot be verified.
+type MatchPolicy string
+
+const (
+	// MatchPolicyEnforce drops unverifiable versions entirely (the
+	// default).
+	MatchPolicyEnforce MatchPolicy = "enforce"
+	// MatchPolicyWarn keeps unverifiable versions but marks them
+	// Verified=false instead of dropping them.
+	MatchPolicyWarn MatchPolicy = "warn"
+)
+
+// RepositoryVerify requires OCIWatcher to check chart signatures before a
+// Component is created for them. It is added to RepositorySpec as the
+// `verify` field.
+type RepositoryVerify struct {
+	// Provider is the signature scheme to verify against.
+	Provider VerifyProvider `json:"provider"`
+
+	// SecretRef names a Secret in the Repository's namespace holding the
+	// provider's public key. Mutually exclusive with Keyless.
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// Keyless configures cosign/notation keyless (Fulcio/Rekor) verification.
+	// Mutually exclusive with SecretRef.
+	// +optional
+	Keyless *KeylessVerify `json:"keyless,omitempty"`
+
+	// MatchPolicy controls the fate of a version that fails verification.
+	// Defaults to MatchPolicyEnforce.
+	// +optional
+	MatchPolicy MatchPolicy `json:"matchPolicy,omitempty"`
+}
+
+// KeylessVerify is the identity an unsigned (keyless) cosign/notation
+// signature must have been issued to.
+type KeylessVerify struct {
+	// Issuer is the expected OIDC issuer, e.g. https://token.actions.githubusercontent.com.
+	Issuer string `json:"issuer"`
+	// Identity is a regular expression the signer's SAN must match, e.g.
+	// the GitHub Actions workflow identity that's allowed to sign releases.
+	Identity string `json:"identity"`
+}
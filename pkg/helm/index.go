@@ -0,0 +1,36 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"helm.sh/helm/v3/pkg/helmpath"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// LoadRepoIndex loads the cached index.yaml for repoName, the file
+// RepoAdd/RepoUpdate maintain under Helm's repository cache directory.
+func LoadRepoIndex(repoName string) (*repo.IndexFile, error) {
+	path := filepath.Join(helmpath.CachePath("repository"), fmt.Sprintf("%s-index.yaml", repoName))
+	idx, err := repo.LoadIndexFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cached index for %q: %w", repoName, err)
+	}
+	return idx, nil
+}
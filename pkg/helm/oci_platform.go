@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+
+	"github.com/kubebb/core/api/v1alpha1"
+)
+
+// ResolveVariants inspects the manifest at ref: a plain chart manifest
+// yields a single implicit variant, while an
+// application/vnd.oci.image.index.v1+json index yields one variant per
+// platform-specific sub-manifest, narrowed to selector when non-empty.
+func ResolveVariants(ctx context.Context, repo *remote.Repository, ref string, selector v1alpha1.PlatformSelector) ([]v1alpha1.ComponentVariant, error) {
+	desc, manifestBytes, err := repo.FetchReference(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest for %s: %w", ref, err)
+	}
+
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageIndex:
+		var index ocispec.Index
+		if err := json.Unmarshal(manifestBytes, &index); err != nil {
+			return nil, fmt.Errorf("failed to decode image index for %s: %w", ref, err)
+		}
+		variants := make([]v1alpha1.ComponentVariant, 0, len(index.Manifests))
+		for _, m := range index.Manifests {
+			if m.Platform == nil {
+				continue
+			}
+			p := v1alpha1.Platform{OS: m.Platform.OS, Arch: m.Platform.Architecture}
+			if !selector.Matches(p) {
+				continue
+			}
+			subManifest, err := content.FetchAll(ctx, repo, m)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch %s manifest for %s: %w", p, ref, err)
+			}
+			variants = append(variants, v1alpha1.ComponentVariant{
+				Platform:   p,
+				Digest:     m.Digest.String(),
+				MediaTypes: manifestLayerMediaTypes(subManifest),
+			})
+		}
+		return variants, nil
+	default:
+		return []v1alpha1.ComponentVariant{{
+			Digest:     desc.Digest.String(),
+			MediaTypes: manifestLayerMediaTypes(manifestBytes),
+		}}, nil
+	}
+}
+
+// manifestLayerMediaTypes extracts the media type of each layer in a plain
+// (non-index) OCI manifest, e.g. the chart content layer plus, when present,
+// a provenance or values-overlay layer.
+func manifestLayerMediaTypes(manifestBytes []byte) []string {
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil
+	}
+	types := make([]string, 0, len(manifest.Layers))
+	for _, l := range manifest.Layers {
+		types = append(types, l.MediaType)
+	}
+	return types
+}
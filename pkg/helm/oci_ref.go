@@ -0,0 +1,244 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-logr/logr"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/repo"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubebb/core/api/v1alpha1"
+	"github.com/kubebb/core/pkg/utils"
+)
+
+// RegistryAuth is the set of credentials resolved from a RepositoryAuth,
+// ready to be handed to Helm's registry client.
+type RegistryAuth struct {
+	Username string
+	Password string
+}
+
+// ResolveAuth turns a v1alpha1.RepositoryAuth into RegistryAuth by reading the
+// referenced Secret directly, or by harvesting the imagePullSecrets of the
+// referenced ServiceAccount.
+func ResolveAuth(ctx context.Context, clientset kubernetes.Interface, namespace string, auth *v1alpha1.RepositoryAuth) (*RegistryAuth, error) {
+	if auth == nil {
+		return nil, nil
+	}
+	switch {
+	case auth.SecretRef != "":
+		secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, auth.SecretRef, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get auth secret %s/%s: %w", namespace, auth.SecretRef, err)
+		}
+		return dockerConfigJSONToAuth(secret)
+	case auth.ServiceAccountName != "":
+		sa, err := clientset.CoreV1().ServiceAccounts(namespace).Get(ctx, auth.ServiceAccountName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service account %s/%s: %w", namespace, auth.ServiceAccountName, err)
+		}
+		for _, ref := range sa.ImagePullSecrets {
+			secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get imagePullSecret %s/%s: %w", namespace, ref.Name, err)
+			}
+			if a, err := dockerConfigJSONToAuth(secret); err == nil && a != nil {
+				return a, nil
+			}
+		}
+		return nil, fmt.Errorf("no usable imagePullSecrets found on service account %s/%s", namespace, auth.ServiceAccountName)
+	default:
+		return nil, nil
+	}
+}
+
+func dockerConfigJSONToAuth(secret *v1.Secret) (*RegistryAuth, error) {
+	data, ok := secret.Data[v1.DockerConfigJsonKey]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no %s key", secret.Namespace, secret.Name, v1.DockerConfigJsonKey)
+	}
+	username, password, err := parseDockerConfigJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistryAuth{Username: username, Password: password}, nil
+}
+
+type dockerConfigJSON struct {
+	Auths map[string]struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Auth     string `json:"auth"`
+	} `json:"auths"`
+}
+
+// parseDockerConfigJSON extracts the first usable username/password pair out
+// of a .dockerconfigjson payload. Entries are tried in Go map iteration
+// order, so when the payload lists several registries which one supplies the
+// credentials is not defined; entries are not matched against the registry
+// host. dockerconfigjson secrets created for a single registry are the common
+// case.
+func parseDockerConfigJSON(data []byte) (username, password string, err error) {
+	var cfg dockerConfigJSON
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return "", "", fmt.Errorf("invalid dockerconfigjson: %w", err)
+	}
+	for _, entry := range cfg.Auths {
+		if entry.Username != "" {
+			return entry.Username, entry.Password, nil
+		}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				continue
+			}
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) == 2 {
+				return parts[0], parts[1], nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("no credentials found in dockerconfigjson")
+}
+
+// ResolveRef resolves a v1alpha1.RepositoryRef against the tags available for
+// an OCI repository, returning the selected tag and its digest.
+//
+//   - Digest: used as-is, no registry call needed to pick a tag.
+//   - Tag: looked up directly against the registry's tag list.
+//   - SemVer: every tag is parsed as a semver and the highest one inside the
+//     range is picked.
+func ResolveRef(ref *v1alpha1.RepositoryRef, tags []string, resolveDigest func(tag string) (string, error)) (tag, digest string, err error) {
+	if ref == nil {
+		return "", "", fmt.Errorf("ref is required")
+	}
+	switch {
+	case ref.Digest != "":
+		return "", ref.Digest, nil
+	case ref.Tag != "":
+		for _, t := range tags {
+			if t == ref.Tag {
+				d, err := resolveDigest(t)
+				return t, d, err
+			}
+		}
+		return "", "", fmt.Errorf("tag %q not found in registry", ref.Tag)
+	case ref.SemVer != "":
+		constraint, err := semver.NewConstraint(ref.SemVer)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid semver range %q: %w", ref.SemVer, err)
+		}
+		var best *semver.Version
+		var bestTag string
+		for _, t := range tags {
+			v, err := semver.NewVersion(t)
+			if err != nil {
+				continue
+			}
+			if !constraint.Check(v) {
+				continue
+			}
+			if best == nil || v.GreaterThan(best) {
+				best, bestTag = v, t
+			}
+		}
+		if best == nil {
+			return "", "", fmt.Errorf("no tag matches semver range %q", ref.SemVer)
+		}
+		d, err := resolveDigest(bestTag)
+		return bestTag, d, err
+	default:
+		return "", "", fmt.Errorf("ref must set exactly one of tag, digest or semver")
+	}
+}
+
+// GetOCIRepoChartsForRef behaves like GetOCIRepoCharts, but instead of
+// returning every tag in the registry it resolves instance.Spec.Ref first
+// (tag/digest/semver) and narrows the result to that single ComponentVersion.
+// Credentials are resolved from instance.Spec.Auth and logged into the OCI
+// registry client used under the hood, rather than relying solely on the
+// kube REST config as GetOCIRepoCharts does.
+func GetOCIRepoChartsForRef(ctx context.Context, getter *genericclioptions.ConfigFlags, c client.Client, clientset kubernetes.Interface, logger logr.Logger, namespace string, instance *v1alpha1.Repository) (latest *chart.Metadata, resolved *repo.ChartVersion, err error) {
+	if instance.Spec.Ref == nil {
+		return nil, nil, fmt.Errorf("GetOCIRepoChartsForRef requires spec.ref to be set")
+	}
+
+	latestMeta, all, err := GetOCIRepoCharts(ctx, getter, c, logger, namespace, instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	regClient, err := registry.NewClient()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create registry client: %w", err)
+	}
+	if auth, err := ResolveAuth(ctx, clientset, namespace, instance.Spec.Auth); err != nil {
+		logger.Error(err, "failed to resolve registry auth, continuing with anonymous access")
+	} else if auth != nil {
+		if err := regClient.Login(utils.GetOCIEntryName(instance.Spec.URL), registry.LoginOptBasicAuth(auth.Username, auth.Password)); err != nil {
+			return nil, nil, fmt.Errorf("failed to authenticate to registry: %w", err)
+		}
+	}
+
+	tags := make([]string, 0, len(all))
+	byTag := make(map[string]int, len(all))
+	for i, v := range all {
+		tags = append(tags, v.Version)
+		byTag[v.Version] = i
+	}
+
+	tag, digest, err := ResolveRef(instance.Spec.Ref, tags, func(t string) (string, error) {
+		return all[byTag[t]].Digest, nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve ref: %w", err)
+	}
+
+	if idx, ok := byTag[tag]; ok {
+		v := all[idx]
+		v.Digest = digest
+		return latestMeta, v, nil
+	}
+
+	// Digest-only pins don't correspond to any tag we listed above, so the
+	// manifest has to be fetched directly by digest to get real chart
+	// metadata instead of fabricating an empty one.
+	result, err := regClient.Pull(fmt.Sprintf("%s@%s", instance.Spec.URL, digest), registry.PullOptWithChart(true))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to pull manifest for digest %s: %w", digest, err)
+	}
+	ch, err := loader.LoadArchive(bytes.NewReader(result.Chart.Data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode chart layer for digest %s: %w", digest, err)
+	}
+	return latestMeta, &repo.ChartVersion{Metadata: ch.Metadata, Digest: result.Manifest.Digest}, nil
+}
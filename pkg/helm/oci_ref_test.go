@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kubebb/core/api/v1alpha1"
+)
+
+func TestResolveRef(t *testing.T) {
+	digestFor := func(t string) (string, error) {
+		return fmt.Sprintf("sha256:%s", t), nil
+	}
+
+	tests := []struct {
+		name       string
+		ref        *v1alpha1.RepositoryRef
+		tags       []string
+		wantTag    string
+		wantDigest string
+		wantErr    bool
+	}{
+		{
+			name:       "digest used as-is",
+			ref:        &v1alpha1.RepositoryRef{Digest: "sha256:abc"},
+			wantTag:    "",
+			wantDigest: "sha256:abc",
+		},
+		{
+			name:       "tag found",
+			ref:        &v1alpha1.RepositoryRef{Tag: "v1.0.0"},
+			tags:       []string{"v0.9.0", "v1.0.0"},
+			wantTag:    "v1.0.0",
+			wantDigest: "sha256:v1.0.0",
+		},
+		{
+			name:    "tag not found",
+			ref:     &v1alpha1.RepositoryRef{Tag: "v2.0.0"},
+			tags:    []string{"v1.0.0"},
+			wantErr: true,
+		},
+		{
+			name:       "semver picks highest match",
+			ref:        &v1alpha1.RepositoryRef{SemVer: "^1.0.0"},
+			tags:       []string{"v0.9.0", "v1.0.0", "v1.2.0", "v2.0.0"},
+			wantTag:    "v1.2.0",
+			wantDigest: "sha256:v1.2.0",
+		},
+		{
+			name:    "semver with no matching tag",
+			ref:     &v1alpha1.RepositoryRef{SemVer: "^3.0.0"},
+			tags:    []string{"v1.0.0"},
+			wantErr: true,
+		},
+		{
+			name:    "nil ref",
+			wantErr: true,
+		},
+		{
+			name:    "empty ref",
+			ref:     &v1alpha1.RepositoryRef{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, digest, err := ResolveRef(tt.ref, tt.tags, digestFor)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got tag=%q digest=%q", tag, digest)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tag != tt.wantTag || digest != tt.wantDigest {
+				t.Fatalf("got tag=%q digest=%q, want tag=%q digest=%q", tag, digest, tt.wantTag, tt.wantDigest)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/registry"
+
+	"github.com/kubebb/core/api/v1alpha1"
+)
+
+// StreamedChart is the result of pulling a chart straight into memory: just
+// enough of the package to populate a Component, without ever writing to
+// disk the way the Helm repository cache does.
+type StreamedChart struct {
+	Metadata *chart.Metadata
+	Values   map[string]interface{}
+	Digest   string
+}
+
+// ResolvePullRef turns a v1alpha1.RepositoryRef (tag/digest/semver, possibly
+// nil) into the fully-qualified ref regClient.Pull expects: "url:tag" or
+// "url@digest". A nil ref defaults to the "latest" tag, matching the
+// on-disk-cache backends' behavior when no ref is pinned. Resolving a semver
+// range needs the registry's tag list, so this makes its own registry.Client
+// rather than requiring callers to thread one through.
+func ResolvePullRef(registryURL string, ref *v1alpha1.RepositoryRef) (string, error) {
+	if ref == nil {
+		return fmt.Sprintf("%s:latest", registryURL), nil
+	}
+	if ref.Digest != "" {
+		return fmt.Sprintf("%s@%s", registryURL, ref.Digest), nil
+	}
+	if ref.Tag != "" {
+		return fmt.Sprintf("%s:%s", registryURL, ref.Tag), nil
+	}
+
+	regClient, err := registry.NewClient()
+	if err != nil {
+		return "", fmt.Errorf("failed to create registry client: %w", err)
+	}
+	tags, err := regClient.Tags(registryURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to list tags for %s: %w", registryURL, err)
+	}
+	tag, _, err := ResolveRef(ref, tags, func(string) (string, error) { return "", nil })
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve ref: %w", err)
+	}
+	return fmt.Sprintf("%s:%s", registryURL, tag), nil
+}
+
+// GetOCIRepoChartsStream pulls the OCI manifest for pullRef, finds the layer
+// carrying the packaged chart, and decodes it directly from the pulled bytes
+// -- no repo cache directory, no temp files. It is the StorageMemory
+// counterpart to GetOCIRepoCharts/GetOCIRepoChartsForRef, both of which rely
+// on Helm's on-disk repository cache. pullRef must already be fully resolved
+// (see ResolvePullRef) since "url:tag" and "url@digest" need different
+// separators and a plain tag can't express a digest or semver pin.
+func GetOCIRepoChartsStream(ctx context.Context, logger logr.Logger, registryURL, pullRef string, auth *RegistryAuth) (*StreamedChart, error) {
+	regClient, err := registry.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %w", err)
+	}
+	if auth != nil {
+		if err := regClient.Login(registryURL, registry.LoginOptBasicAuth(auth.Username, auth.Password)); err != nil {
+			return nil, fmt.Errorf("failed to authenticate to registry: %w", err)
+		}
+	}
+
+	result, err := regClient.Pull(pullRef, registry.PullOptWithChart(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull %s: %w", pullRef, err)
+	}
+
+	ch, err := loader.LoadArchive(bytes.NewReader(result.Chart.Data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode chart layer: %w", err)
+	}
+
+	logger.V(1).Info("streamed chart into memory", "ref", pullRef, "digest", result.Manifest.Digest)
+	return &StreamedChart{
+		Metadata: ch.Metadata,
+		Values:   ch.Values,
+		Digest:   result.Manifest.Digest,
+	}, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubebb/core/api/v1alpha1"
+)
+
+func TestVerifyChartDigest_NilVerify(t *testing.T) {
+	result, err := VerifyChartDigest(context.Background(), nil, "ns", "registry/chart", "sha256:abc", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Verified {
+		t.Fatalf("expected Verified=false when spec.verify is unset")
+	}
+}
+
+func TestVerifyChartDigest_UnknownProvider(t *testing.T) {
+	verify := &v1alpha1.RepositoryVerify{Provider: "bogus"}
+	if _, err := VerifyChartDigest(context.Background(), nil, "ns", "registry/chart", "sha256:abc", verify); err == nil {
+		t.Fatal("expected an error for an unknown provider")
+	}
+}
+
+func TestVerifyChartDigest_NotationNotImplemented(t *testing.T) {
+	verify := &v1alpha1.RepositoryVerify{Provider: v1alpha1.VerifyProviderNotation}
+	if _, err := VerifyChartDigest(context.Background(), nil, "ns", "registry/chart", "sha256:abc", verify); err == nil {
+		t.Fatal("expected notation verification to report not implemented")
+	}
+}
+
+// TestVerifyCosign_KeylessFailsClosed guards against verifyCosign silently
+// rubber-stamping a keyless config as Verified=true: with no Fulcio/Rekor
+// verification in place, keyless must fail closed.
+func TestVerifyCosign_KeylessFailsClosed(t *testing.T) {
+	verify := &v1alpha1.RepositoryVerify{
+		Provider: v1alpha1.VerifyProviderCosign,
+		Keyless:  &v1alpha1.KeylessVerify{Issuer: "https://token.actions.githubusercontent.com", Identity: ".*"},
+	}
+	result, err := VerifyChartDigest(context.Background(), nil, "ns", "registry/chart", "sha256:abc", verify)
+	if err == nil {
+		t.Fatal("expected keyless verification to fail closed with an error")
+	}
+	if result.Verified {
+		t.Fatal("expected Verified=false for an unimplemented keyless path")
+	}
+}
+
+// TestVerifyCosign_KeylessWarnKeepsUnverified checks that matchPolicy: warn
+// turns the keyless "not implemented" refusal into a kept-but-unverified
+// result instead of an error, same as a failed key-based check under warn.
+func TestVerifyCosign_KeylessWarnKeepsUnverified(t *testing.T) {
+	verify := &v1alpha1.RepositoryVerify{
+		Provider:    v1alpha1.VerifyProviderCosign,
+		Keyless:     &v1alpha1.KeylessVerify{Issuer: "https://token.actions.githubusercontent.com", Identity: ".*"},
+		MatchPolicy: v1alpha1.MatchPolicyWarn,
+	}
+	result, err := VerifyChartDigest(context.Background(), nil, "ns", "registry/chart", "sha256:abc", verify)
+	if err != nil {
+		t.Fatalf("expected matchPolicy: warn to suppress the error, got: %v", err)
+	}
+	if result.Verified {
+		t.Fatal("expected Verified=false for an unimplemented keyless path")
+	}
+}
+
+func TestVerifyCosign_RequiresSecretRefOrKeyless(t *testing.T) {
+	verify := &v1alpha1.RepositoryVerify{Provider: v1alpha1.VerifyProviderCosign}
+	if _, err := VerifyChartDigest(context.Background(), nil, "ns", "registry/chart", "sha256:abc", verify); err == nil {
+		t.Fatal("expected an error when neither secretRef nor keyless is set")
+	}
+}
+
+func TestLoadPublicKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate test key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal test key: %v", err)
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cosign-key"},
+		Data:       map[string][]byte{"cosign.pub": pemBytes},
+	}
+	if _, err := loadPublicKey(secret); err != nil {
+		t.Fatalf("unexpected error loading a valid cosign.pub: %v", err)
+	}
+}
+
+func TestLoadPublicKey_MissingKey(t *testing.T) {
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cosign-key"}}
+	if _, err := loadPublicKey(secret); err == nil {
+		t.Fatal("expected an error when the secret has no cosign.pub key")
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"helm.sh/helm/v3/pkg/chart"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubebb/core/api/v1alpha1"
+)
+
+// ChartVersion is a single resolvable version of a chart, independent of
+// which backend (Helm HTTP index, OCI registry, Git repo...) produced it.
+// Name identifies which chart this version belongs to: backends that can see
+// more than one chart (e.g. every entry in a Helm index, or every chart in a
+// Git repo) set it so versions of distinct charts are never conflated; a
+// backend that only ever sees one chart may leave it empty.
+type ChartVersion struct {
+	Name       string
+	Version    string
+	AppVersion string
+	Digest     string
+	Deprecated bool
+}
+
+// ChartArtifact is the fetched content of one ChartVersion: enough to build
+// a Component the same way OCIWatcher.Poll does today.
+type ChartArtifact struct {
+	Metadata *chart.Metadata
+	Values   map[string]interface{}
+	Digest   string
+}
+
+// ChartSource is implemented by the registered chart-source backends (Helm
+// HTTP index, Git). SourceWatcher is the generic polling loop driven by one
+// of these; helm-oci Repositories are served by OCIWatcher instead, which
+// does not use a ChartSource (see ChartSourceTypeOCI).
+type ChartSource interface {
+	// Resolve lists the versions available for ref, a backend-specific
+	// selector (e.g. a chart name for the http backend); an empty ref lists
+	// every version the backend can see, across every chart the backend
+	// exposes, each tagged with its own ChartVersion.Name.
+	Resolve(ctx context.Context, ref string) ([]ChartVersion, error)
+	// Fetch retrieves the chart content for one ChartVersion previously
+	// returned by Resolve.
+	Fetch(ctx context.Context, version ChartVersion) (ChartArtifact, error)
+}
+
+// ChartSourceFactory builds a ChartSource for a given Repository.
+type ChartSourceFactory func(instance *v1alpha1.Repository, c client.Client, logger logr.Logger) (ChartSource, error)
+
+// backends holds the registered factories, keyed by Repository.Spec.Type.
+var backends = map[string]ChartSourceFactory{}
+
+// RegisterChartSource registers a backend factory under name, so that
+// Repositories with spec.type: <name> are served by it. Called from each
+// backend's init().
+func RegisterChartSource(name string, factory ChartSourceFactory) {
+	backends[name] = factory
+}
+
+// NewChartSource looks up the backend registered for instance.Spec.Type,
+// defaulting to RepositoryTypeHelmHTTP when Type is unset (matching its
+// doc comment and covering Repositories persisted before Type existed).
+func NewChartSource(instance *v1alpha1.Repository, c client.Client, logger logr.Logger) (ChartSource, error) {
+	t := instance.Spec.Type
+	if t == "" {
+		t = v1alpha1.RepositoryTypeHelmHTTP
+	}
+	factory, ok := backends[t]
+	if !ok {
+		return nil, fmt.Errorf("no chart source backend registered for type %q", t)
+	}
+	return factory(instance, c, logger)
+}
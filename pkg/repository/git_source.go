@@ -0,0 +1,189 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	gitignore "github.com/sabhiram/go-gitignore"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubebb/core/api/v1alpha1"
+)
+
+// chartWithPath is the subset of a loaded chart.Chart that ChartArtifact
+// needs, keyed by its repo-relative path while Resolve scans a checkout.
+// Nothing is kept between Resolve and Fetch: Fetch clones again and loads
+// the chart at that path.
+type chartWithPath struct {
+	Metadata *chart.Metadata
+	Values   map[string]interface{}
+}
+
+const ChartSourceTypeGit = v1alpha1.RepositoryTypeGit
+
+func init() {
+	RegisterChartSource(ChartSourceTypeGit, newGitChartSource)
+}
+
+// gitChartSource clones a Git repository and scans it for Chart.yaml files,
+// letting Git monorepos be consumed the same way a Helm repository index is.
+type gitChartSource struct {
+	instance *v1alpha1.Repository
+	logger   logr.Logger
+}
+
+func newGitChartSource(instance *v1alpha1.Repository, c client.Client, logger logr.Logger) (ChartSource, error) {
+	return &gitChartSource{instance: instance, logger: logger}, nil
+}
+
+// Resolve clones the repository at the configured ref and returns one
+// ChartVersion per Chart.yaml found, keyed by its path so Fetch can locate it
+// again without re-scanning.
+func (s *gitChartSource) Resolve(ctx context.Context, ref string) ([]ChartVersion, error) {
+	dir, _, err := s.checkout(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	charts, err := s.scanCharts(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]ChartVersion, 0, len(charts))
+	for path, ch := range charts {
+		versions = append(versions, ChartVersion{
+			Name:       ch.Metadata.Name,
+			Version:    path,
+			AppVersion: ch.Metadata.AppVersion,
+			Deprecated: ch.Metadata.Deprecated,
+		})
+	}
+	return versions, nil
+}
+
+// Fetch re-clones the repository and loads the chart at the path recorded by
+// Resolve as version.Version.
+func (s *gitChartSource) Fetch(ctx context.Context, version ChartVersion) (ChartArtifact, error) {
+	dir, _, err := s.checkout(ctx)
+	if err != nil {
+		return ChartArtifact{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	ch, err := loader.Load(filepath.Join(dir, version.Version))
+	if err != nil {
+		return ChartArtifact{}, fmt.Errorf("failed to load chart at %s: %w", version.Version, err)
+	}
+	return ChartArtifact{Metadata: ch.Metadata, Values: ch.Values}, nil
+}
+
+func (s *gitChartSource) checkout(ctx context.Context) (dir string, head string, err error) {
+	dir, err = os.MkdirTemp("", "kubebb-git-source-*")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create checkout dir: %w", err)
+	}
+
+	opts := &git.CloneOptions{URL: s.instance.Spec.URL}
+	gitRef := s.instance.Spec.Git.Ref
+	switch {
+	case gitRef.Branch != "":
+		opts.ReferenceName = plumbing.NewBranchReferenceName(gitRef.Branch)
+	case gitRef.Tag != "":
+		opts.ReferenceName = plumbing.NewTagReferenceName(gitRef.Tag)
+	}
+
+	repo, err := git.PlainCloneContext(ctx, dir, false, opts)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", "", fmt.Errorf("failed to clone %s: %w", s.instance.Spec.URL, err)
+	}
+
+	if gitRef.Commit != "" {
+		wt, err := repo.Worktree()
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", "", err
+		}
+		if err := wt.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(gitRef.Commit)}); err != nil {
+			os.RemoveAll(dir)
+			return "", "", fmt.Errorf("failed to checkout commit %s: %w", gitRef.Commit, err)
+		}
+	}
+
+	h, err := repo.Head()
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", "", err
+	}
+	return dir, h.Hash().String(), nil
+}
+
+// scanCharts walks dir for Chart.yaml files, skipping anything matched by
+// Spec.Git.Ignore.
+func (s *gitChartSource) scanCharts(dir string) (map[string]*chartWithPath, error) {
+	var ignore *gitignore.GitIgnore
+	if len(s.instance.Spec.Git.Ignore) > 0 {
+		ignore = gitignore.CompileIgnoreLines(s.instance.Spec.Git.Ignore...)
+	}
+
+	charts := make(map[string]*chartWithPath)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if ignore != nil && ignore.MatchesPath(rel) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() || info.Name() != "Chart.yaml" {
+			return nil
+		}
+		chartDir := filepath.Dir(path)
+		ch, err := loader.Load(chartDir)
+		if err != nil {
+			s.logger.Error(err, "failed to load chart, skipping", "path", chartDir)
+			return nil
+		}
+		relChartDir, err := filepath.Rel(dir, chartDir)
+		if err != nil {
+			return err
+		}
+		charts[relChartDir] = &chartWithPath{Metadata: ch.Metadata, Values: ch.Values}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s for charts: %w", dir, err)
+	}
+	return charts, nil
+}
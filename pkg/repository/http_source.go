@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubebb/core/api/v1alpha1"
+	"github.com/kubebb/core/pkg/helm"
+)
+
+const ChartSourceTypeHTTP = v1alpha1.RepositoryTypeHelmHTTP
+
+func init() {
+	RegisterChartSource(ChartSourceTypeHTTP, newHTTPChartSource)
+}
+
+// httpChartSource wraps Helm's classic index.yaml repositories, the same
+// path the Watcher family already uses, behind ChartSource.
+type httpChartSource struct {
+	instance *v1alpha1.Repository
+	logger   logr.Logger
+}
+
+func newHTTPChartSource(instance *v1alpha1.Repository, c client.Client, logger logr.Logger) (ChartSource, error) {
+	return &httpChartSource{instance: instance, logger: logger}, nil
+}
+
+func (s *httpChartSource) index(ctx context.Context) (*repo.IndexFile, error) {
+	name := s.instance.NamespacedName()
+	if err := helm.RepoUpdate(ctx, s.logger, name, 0); err != nil {
+		return nil, fmt.Errorf("failed to update helm repository index: %w", err)
+	}
+	return helm.LoadRepoIndex(name)
+}
+
+// Resolve lists every version of ref (a chart name), or of every chart in the
+// index when ref is empty, each tagged with its own ChartVersion.Name.
+func (s *httpChartSource) Resolve(ctx context.Context, ref string) ([]ChartVersion, error) {
+	idx, err := s.index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ref != "" {
+		entries, ok := idx.Entries[ref]
+		if !ok {
+			return nil, fmt.Errorf("chart %q not found in repository index", ref)
+		}
+		return chartVersionsFromEntries(ref, entries), nil
+	}
+	versions := make([]ChartVersion, 0, len(idx.Entries))
+	for name, entries := range idx.Entries {
+		versions = append(versions, chartVersionsFromEntries(name, entries)...)
+	}
+	return versions, nil
+}
+
+func chartVersionsFromEntries(name string, entries repo.ChartVersions) []ChartVersion {
+	versions := make([]ChartVersion, 0, len(entries))
+	for _, v := range entries {
+		versions = append(versions, ChartVersion{
+			Name:       name,
+			Version:    v.Version,
+			AppVersion: v.AppVersion,
+			Digest:     v.Digest,
+			Deprecated: v.Deprecated,
+		})
+	}
+	return versions
+}
+
+// Fetch downloads and decodes the chart archive backing one ChartVersion
+// returned by Resolve.
+func (s *httpChartSource) Fetch(ctx context.Context, version ChartVersion) (ChartArtifact, error) {
+	name := version.Name
+
+	idx, err := s.index(ctx)
+	if err != nil {
+		return ChartArtifact{}, err
+	}
+	entries, ok := idx.Entries[name]
+	if !ok {
+		return ChartArtifact{}, fmt.Errorf("chart %q not found in repository index", name)
+	}
+	var entry *repo.ChartVersion
+	for _, v := range entries {
+		if v.Version == version.Version {
+			entry = v
+			break
+		}
+	}
+	if entry == nil || len(entry.URLs) == 0 {
+		return ChartArtifact{}, fmt.Errorf("chart %s:%s has no download URL", name, version.Version)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, entry.URLs[0], nil)
+	if err != nil {
+		return ChartArtifact{}, fmt.Errorf("failed to build download request for %s: %w", entry.URLs[0], err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return ChartArtifact{}, fmt.Errorf("failed to download %s: %w", entry.URLs[0], err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ChartArtifact{}, fmt.Errorf("failed to download %s: unexpected status %s", entry.URLs[0], resp.Status)
+	}
+
+	ch, err := loader.LoadArchive(resp.Body)
+	if err != nil {
+		return ChartArtifact{}, fmt.Errorf("failed to decode chart archive from %s: %w", entry.URLs[0], err)
+	}
+	return ChartArtifact{Metadata: ch.Metadata, Values: ch.Values, Digest: entry.Digest}, nil
+}
@@ -0,0 +1,27 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+// ChartSourceTypeOCI is never registered with RegisterChartSource: unlike
+// the http and git backends, helm-oci isn't served through ChartSource at
+// all. OCIWatcher predates the ChartSource abstraction and already covers
+// everything it would need (ref pinning, in-memory streaming, signature
+// verification, platform variants), none of which fit the generic
+// Resolve/Fetch shape yet, so NewWatcher routes helm-oci to it directly
+// instead of through a ChartSource adapter that could only ever duplicate
+// OCIWatcher's logic.
+const ChartSourceTypeOCI = "helm-oci"
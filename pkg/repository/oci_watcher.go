@@ -22,12 +22,17 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -85,23 +90,56 @@ func (c *OCIWatcher) Start() error {
 	entry.Name = c.repoName
 	entry.URL = c.instance.Spec.URL
 
-	if err := helm.RepoAdd(c.ctx, c.logger, entry, c.duration/2); err != nil {
-		c.logger.Error(err, "Failed to add repository")
-		return err
+	// StorageMemory never touches Helm's on-disk repository cache, so there
+	// is nothing to add here.
+	if c.instance.Spec.Storage != v1alpha1.StorageMemory {
+		if err := helm.RepoAdd(c.ctx, c.logger, entry, c.duration/2); err != nil {
+			c.logger.Error(err, "Failed to add repository")
+			return err
+		}
 	}
 
-	go wait.Until(c.Poll, c.duration, c.ctx.Done())
+	if c.instance.Spec.Webhook != nil {
+		cfg, err := ctrl.GetConfig()
+		if err != nil {
+			c.logger.Error(err, "Cannot get config, webhook trigger will not be registered")
+		} else if clientset, err := kubernetes.NewForConfig(cfg); err != nil {
+			c.logger.Error(err, "Cannot build clientset, webhook trigger will not be registered")
+		} else {
+			RegisterWebhookTarget(c.instance, c.c, clientset, c.pollFromWebhook)
+		}
+	}
+
+	go wait.Until(func() {
+		periodicSyncsTotal.Inc()
+		c.Poll()
+	}, c.duration, c.ctx.Done())
 	return nil
 }
 
 func (c *OCIWatcher) Stop() {
 	c.logger.Info("Delete Or Update Repository, stop watcher")
-	if err := helm.RepoRemove(c.ctx, c.logger, c.repoName); err != nil {
-		c.logger.Error(err, "Failed to remove repository")
+	if c.instance.Spec.Storage != v1alpha1.StorageMemory {
+		if err := helm.RepoRemove(c.ctx, c.logger, c.repoName); err != nil {
+			c.logger.Error(err, "Failed to remove repository")
+		}
 	}
+	UnregisterWebhookTarget(c.instance)
 	c.cancel()
 }
 
+// pollFromWebhook is called by the webhook receiver instead of Poll
+// directly, so LastWebhookAt reflects that this sync was event-driven
+// rather than the periodic fallback.
+func (c *OCIWatcher) pollFromWebhook() {
+	now := metav1.Now()
+	c.instance.Status.LastWebhookAt = &now
+	if err := c.c.Status().Update(c.ctx, c.instance); err != nil {
+		c.logger.Error(err, "failed to record LastWebhookAt")
+	}
+	c.Poll()
+}
+
 // Poll the components
 func (c *OCIWatcher) Poll() {
 	c.logger.Info("OCI poll")
@@ -109,9 +147,11 @@ func (c *OCIWatcher) Poll() {
 	readyCond := getReadyCond(now)
 	syncCond := getSyncCond(now)
 
-	if err := helm.RepoUpdate(c.ctx, c.logger, c.repoName, c.duration/2); err != nil {
-		c.logger.Error(err, "Failed to update repository")
-		return
+	if c.instance.Spec.Storage != v1alpha1.StorageMemory {
+		if err := helm.RepoUpdate(c.ctx, c.logger, c.repoName, c.duration/2); err != nil {
+			c.logger.Error(err, "Failed to update repository")
+			return
+		}
 	}
 	entryName := utils.GetOCIEntryName(c.instance.Spec.URL)
 	cfg, err := ctrl.GetConfig()
@@ -127,11 +167,49 @@ func (c *OCIWatcher) Poll() {
 		Namespace:   &ns,
 	}
 
-	latest, all, err := helm.GetOCIRepoCharts(c.ctx, &getter, c.c, c.logger, ns, c.instance)
+	clientset, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
-		c.logger.Error(err, "Cannot get oci repo charts")
+		c.logger.Error(err, "Cannot build clientset for registry auth")
 		return
 	}
+	regAuth, err := helm.ResolveAuth(c.ctx, clientset, ns, c.instance.Spec.Auth)
+	if err != nil {
+		c.logger.Error(err, "Cannot resolve registry auth")
+		return
+	}
+
+	var latest *chart.Metadata
+	var all []*repo.ChartVersion
+	var resolved *repo.ChartVersion
+	switch {
+	case c.instance.Spec.Storage == v1alpha1.StorageMemory:
+		pullRef, err := helm.ResolvePullRef(c.instance.Spec.URL, c.instance.Spec.Ref)
+		if err != nil {
+			c.logger.Error(err, "Cannot resolve oci ref")
+			return
+		}
+		streamed, err := helm.GetOCIRepoChartsStream(c.ctx, c.logger, c.instance.Spec.URL, pullRef, regAuth)
+		if err != nil {
+			c.logger.Error(err, "Cannot stream oci chart into memory")
+			return
+		}
+		latest = streamed.Metadata
+		resolved = &repo.ChartVersion{Metadata: streamed.Metadata, Digest: streamed.Digest}
+		all = []*repo.ChartVersion{resolved}
+	case c.instance.Spec.Ref != nil:
+		latest, resolved, err = helm.GetOCIRepoChartsForRef(c.ctx, &getter, c.c, clientset, c.logger, ns, c.instance)
+		if err != nil {
+			c.logger.Error(err, "Cannot resolve pinned oci ref")
+			return
+		}
+		all = []*repo.ChartVersion{resolved}
+	default:
+		latest, all, err = helm.GetOCIRepoCharts(c.ctx, &getter, c.c, c.logger, ns, c.instance)
+		if err != nil {
+			c.logger.Error(err, "Cannot get oci repo charts")
+			return
+		}
+	}
 
 	item := v1alpha1.Component{
 		ObjectMeta: metav1.ObjectMeta{
@@ -171,7 +249,7 @@ func (c *OCIWatcher) Poll() {
 	if keep {
 		for _, idx := range filterVersionIndices {
 			version := all[idx]
-			item.Status.Versions = append(item.Status.Versions, v1alpha1.ComponentVersion{
+			cv := v1alpha1.ComponentVersion{
 				Annotations: version.Annotations,
 				Version:     version.Version,
 				AppVersion:  version.AppVersion,
@@ -179,7 +257,36 @@ func (c *OCIWatcher) Poll() {
 				Digest:      version.Digest,
 				UpdatedAt:   metav1.Now(),
 				Deprecated:  version.Deprecated,
-			})
+			}
+			if c.instance.Spec.Verify != nil {
+				result, err := helm.VerifyChartDigest(c.ctx, clientset, ns, c.instance.Spec.URL, version.Digest, c.instance.Spec.Verify)
+				if err != nil {
+					c.logger.Info("dropping unverified chart version", "version", version.Version, "error", err.Error())
+					continue
+				}
+				cv.Verified = result.Verified
+				cv.VerifiedBy = result.By
+				verifiedAt := metav1.Now()
+				cv.VerifiedAt = &verifiedAt
+			}
+			if ociRepo, err := remote.NewRepository(c.instance.Spec.URL); err != nil {
+				c.logger.Error(err, "cannot inspect manifest for variants", "version", version.Version)
+			} else {
+				if regAuth != nil {
+					ociRepo.Client = &auth.Client{
+						Credential: auth.StaticCredential(ociRepo.Reference.Registry, auth.Credential{
+							Username: regAuth.Username,
+							Password: regAuth.Password,
+						}),
+					}
+				}
+				if variants, err := helm.ResolveVariants(c.ctx, ociRepo, version.Version, c.instance.Spec.PlatformSelector); err != nil {
+					c.logger.Error(err, "failed to resolve platform variants", "version", version.Version)
+				} else {
+					cv.Variants = variants
+				}
+			}
+			item.Status.Versions = append(item.Status.Versions, cv)
 		}
 	}
 	keywords := latest.Keywords
@@ -191,6 +298,10 @@ func (c *OCIWatcher) Poll() {
 	item.Status.Icon = latest.Icon
 	item.Status.Keywords = keywords
 	item.Status.Sources = latest.Sources
+	if resolved != nil {
+		item.Status.ResolvedTag = resolved.Version
+		item.Status.ResolvedDigest = resolved.Digest
+	}
 
 	for _, m := range maintainers {
 		item.Status.Maintainers = append(item.Status.Maintainers, m)
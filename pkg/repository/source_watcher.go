@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kubebb/core/api/v1alpha1"
+)
+
+var _ IWatcher = (*SourceWatcher)(nil)
+
+// NewWatcher is the entry point callers use instead of picking a concrete
+// watcher themselves: it looks up the ChartSource backend registered for
+// instance.Spec.Type and wraps it in a generic polling loop.
+//
+// helm-oci keeps using the dedicated OCIWatcher, which has OCI-specific
+// behavior (ref pinning, in-memory streaming) that doesn't fit the generic
+// Resolve/Fetch shape yet; every other backend goes through SourceWatcher.
+func NewWatcher(
+	instance *v1alpha1.Repository,
+	c client.Client,
+	ctx context.Context,
+	logger logr.Logger,
+	duration time.Duration,
+	cancel context.CancelFunc,
+	scheme *runtime.Scheme,
+	fm map[string]v1alpha1.FilterCond,
+) (IWatcher, error) {
+	if instance.Spec.Type == ChartSourceTypeOCI {
+		return NewOCIWatcher(instance, c, ctx, logger, duration, cancel, scheme, fm), nil
+	}
+
+	source, err := NewChartSource(instance, c, logger)
+	if err != nil {
+		return nil, err
+	}
+	result := &SourceWatcher{
+		instance: instance,
+		source:   source,
+		duration: duration,
+		cancel:   cancel,
+		scheme:   scheme,
+		repoName: instance.NamespacedName(),
+		logger:   logger,
+	}
+	result.c = c
+	result.ctx = ctx
+	return result, nil
+}
+
+// SourceWatcher is the ChartSource-backed counterpart to OCIWatcher: a
+// generic polling loop that doesn't know whether it's talking to a Helm
+// index, a Git monorepo, or anything else registered via RegisterChartSource.
+type SourceWatcher struct {
+	CommonAction
+	instance *v1alpha1.Repository
+	source   ChartSource
+	duration time.Duration
+	cancel   context.CancelFunc
+	scheme   *runtime.Scheme
+	repoName string
+	logger   logr.Logger
+}
+
+func (s *SourceWatcher) Start() error {
+	if _, err := Start(s.ctx, s.instance, s.duration, s.repoName, s.c, s.logger); err != nil {
+		return err
+	}
+	go wait.Until(s.Poll, s.duration, s.ctx.Done())
+	return nil
+}
+
+func (s *SourceWatcher) Stop() {
+	s.logger.Info("Delete Or Update Repository, stop watcher")
+	s.cancel()
+}
+
+// Poll resolves every chart version the backend can see and records one
+// Component per distinct ChartVersion.Name, following the same shape
+// OCIWatcher.Poll builds. Backends that expose more than one chart (e.g. a
+// Helm index with several entries, or a Git monorepo) return all of them
+// from a single Resolve(ctx, "") call, so Poll must not treat that whole
+// slice as one chart's version history.
+func (s *SourceWatcher) Poll() {
+	s.logger.Info("chart source poll", "type", s.instance.Spec.Type)
+	now := metav1.Now()
+	readyCond := getReadyCond(now)
+	syncCond := getSyncCond(now)
+
+	versions, err := s.source.Resolve(s.ctx, "")
+	if err != nil {
+		s.logger.Error(err, "failed to resolve chart versions")
+		return
+	}
+	if len(versions) == 0 {
+		s.logger.Info("no chart versions resolved")
+		return
+	}
+
+	// Group by Name, recording in names the order in which each Name first
+	// appears, so Components are created in the order Resolve returned their
+	// versions. That order is only as stable as the backend's: the http and
+	// git backends build their result by ranging over maps, so it can differ
+	// between polls.
+	byName := make(map[string][]ChartVersion, len(versions))
+	var names []string
+	for _, v := range versions {
+		name := v.Name
+		if name == "" {
+			name = s.instance.GetName()
+		}
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], v)
+	}
+
+	for _, name := range names {
+		if err := s.createComponent(name, byName[name]); err != nil {
+			s.logger.Error(err, "failed to create component", "chart", name)
+		}
+	}
+
+	updateRepository(s.ctx, s.instance, s.c, s.logger, readyCond, syncCond)
+}
+
+// createComponent fetches versions[0] to learn the chart's display metadata,
+// then records a Component named entryName holding every version in
+// versions — all of which share that one chart identity.
+func (s *SourceWatcher) createComponent(entryName string, versions []ChartVersion) error {
+	artifact, err := s.source.Fetch(s.ctx, versions[0])
+	if err != nil {
+		return fmt.Errorf("failed to fetch chart %s@%s: %w", entryName, versions[0].Version, err)
+	}
+	if artifact.Metadata != nil && artifact.Metadata.Name != "" {
+		entryName = artifact.Metadata.Name
+	}
+
+	item := v1alpha1.Component{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%s", s.instance.GetName(), entryName),
+			Namespace: s.instance.GetNamespace(),
+			Labels: map[string]string{
+				v1alpha1.ComponentRepositoryLabel: s.instance.GetName(),
+			},
+		},
+		Status: v1alpha1.ComponentStatus{
+			RepositoryRef: &v1.ObjectReference{
+				Kind:       s.instance.Kind,
+				Name:       s.instance.GetName(),
+				Namespace:  s.instance.GetNamespace(),
+				UID:        s.instance.GetUID(),
+				APIVersion: s.instance.APIVersion,
+			},
+			Name:     entryName,
+			Versions: make([]v1alpha1.ComponentVersion, 0, len(versions)),
+		},
+	}
+	for _, v := range versions {
+		item.Status.Versions = append(item.Status.Versions, v1alpha1.ComponentVersion{
+			Version:    v.Version,
+			AppVersion: v.AppVersion,
+			Digest:     v.Digest,
+			Deprecated: v.Deprecated,
+			UpdatedAt:  metav1.Now(),
+		})
+	}
+
+	_ = controllerutil.SetOwnerReference(s.instance, &item, s.scheme)
+
+	if err := s.Create(&item); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	s.logger.Info("Successfully created component", "Component.Name", item.GetName(), "Component.Namespace", item.GetNamespace())
+	return nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2023 The Kubebb Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/kubebb/core/api/v1alpha1"
+)
+
+var (
+	webhookSyncsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "kubebb_repository_webhook_syncs_total",
+		Help: "Number of OCIWatcher polls triggered by a registry webhook delivery.",
+	})
+	periodicSyncsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "kubebb_repository_periodic_syncs_total",
+		Help: "Number of OCIWatcher polls triggered by the periodic interval fallback.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(webhookSyncsTotal, periodicSyncsTotal)
+}
+
+// registeredRepo is what the webhook handler needs to trigger a Poll for a
+// Repository it was told about via RegisterWebhookTarget.
+type registeredRepo struct {
+	hmacSecret func(ctx context.Context) (string, error)
+	poll       func()
+}
+
+var (
+	webhookMu       sync.RWMutex
+	webhookRegistry = map[string]registeredRepo{} // keyed by Repository UID
+)
+
+// RegisterWebhookTarget makes instance reachable at /webhook/{repo-uid} for
+// the lifetime of its watcher. OCIWatcher.Start calls this; OCIWatcher.Stop
+// calls UnregisterWebhookTarget.
+func RegisterWebhookTarget(instance *v1alpha1.Repository, c client.Client, clientset kubernetes.Interface, poll func()) {
+	if instance.Spec.Webhook == nil {
+		return
+	}
+	webhookMu.Lock()
+	defer webhookMu.Unlock()
+	ns := instance.GetNamespace()
+	secretName := instance.Spec.Webhook.SecretRef
+	webhookRegistry[string(instance.GetUID())] = registeredRepo{
+		hmacSecret: func(ctx context.Context) (string, error) {
+			secret, err := clientset.CoreV1().Secrets(ns).Get(ctx, secretName, metav1.GetOptions{})
+			if err != nil {
+				return "", fmt.Errorf("failed to get webhook secret %s/%s: %w", ns, secretName, err)
+			}
+			return string(secret.Data["secret"]), nil
+		},
+		poll: poll,
+	}
+}
+
+// UnregisterWebhookTarget removes a Repository added by RegisterWebhookTarget.
+func UnregisterWebhookTarget(instance *v1alpha1.Repository) {
+	webhookMu.Lock()
+	defer webhookMu.Unlock()
+	delete(webhookRegistry, string(instance.GetUID()))
+}
+
+// WebhookHandler serves `/webhook/{repo-uid}`: it authenticates the delivery
+// against that Repository's HMAC secret and, on success, triggers an
+// immediate Poll(). It accepts Harbor, GHCR and Docker Hub push payloads;
+// since all three just need to prove the delivery is authentic, the payload
+// itself isn't inspected beyond checking that it decodes as a JSON object
+// (otherwise the delivery is rejected with 400).
+func WebhookHandler(logger logr.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uid := r.PathValue("repo-uid")
+		webhookMu.RLock()
+		target, ok := webhookRegistry[uid]
+		webhookMu.RUnlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+
+		secret, err := target.hmacSecret(r.Context())
+		if err != nil {
+			logger.Error(err, "failed to load webhook secret", "repo-uid", uid)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if !validSignature(secret, body, r.Header.Get("X-Hub-Signature-256")) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		var payload map[string]interface{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+
+		webhookSyncsTotal.Inc()
+		go target.poll()
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// StartWebhookServer serves WebhookHandler at /webhook/{repo-uid} on addr
+// until ctx is cancelled. It must be started once alongside the controller
+// manager (e.g. `go func() { StartWebhookServer(...) }()` next to
+// `mgr.Start(ctx)`) for registry webhook deliveries to ever reach
+// WebhookHandler; RegisterWebhookTarget only makes a Repository's UID known
+// to the handler, it doesn't by itself make the handler reachable over HTTP.
+func StartWebhookServer(ctx context.Context, logger logr.Logger, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/webhook/{repo-uid}", WebhookHandler(logger))
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+
+	logger.Info("starting repository webhook server", "addr", addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("webhook server failed: %w", err)
+	}
+	return nil
+}
+
+// validSignature checks an `X-Hub-Signature-256: sha256=<hex>` header
+// against an HMAC-SHA256 of body keyed by secret (GHCR/Docker Hub's
+// convention; Harbor sends the same header for custom webhook endpoints).
+func validSignature(secret string, body []byte, header string) bool {
+	const prefix = "sha256="
+	if len(header) <= len(prefix) || header[:len(prefix)] != prefix {
+		return false
+	}
+	want, err := hex.DecodeString(header[len(prefix):])
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(want, mac.Sum(nil))
+}